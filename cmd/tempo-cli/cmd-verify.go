@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	tempodb_backend "github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+)
+
+// fileNameBloom and fileNameIndex are the object names written alongside
+// meta.json for every block.
+const (
+	fileNameBloom = "bloom"
+	fileNameIndex = "index"
+)
+
+type verifyCmd struct {
+	backendOptions
+
+	TenantID     string        `arg:"" help:"tenant to scan, required"`
+	HaltOnError  bool          `hidden:"" help:"on the first critical inconsistency, halt and keep the process alive so an operator can attach and inspect"`
+	WarnInterval time.Duration `help:"log at most one transient per-block warning of a given kind per this interval" default:"1s"`
+
+	warn *rateLimitedLogger
+}
+
+type verifyStats struct {
+	mtx sync.Mutex
+
+	scanned  int
+	ok       int
+	warnings int
+	critical int
+}
+
+func (s *verifyStats) record(severity string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.scanned++
+	switch severity {
+	case "warning":
+		s.warnings++
+	case "critical":
+		s.critical++
+	default:
+		s.ok++
+	}
+}
+
+func (cmd *verifyCmd) Run(opts *globalOptions) error {
+	r, _, scan, err := loadBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return err
+	}
+
+	// cancel the scan on Ctrl-C/SIGTERM instead of hard-killing the process
+	// mid-request, so the boundedGroup can unwind in-flight backend calls.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	blockIDs, err := r.Blocks(ctx, cmd.TenantID)
+	if err != nil {
+		return fmt.Errorf("failed to list blocks for tenant %s: %w", cmd.TenantID, err)
+	}
+
+	// MaxInFlightRequests, when set, further caps how many blocks we verify
+	// concurrently on top of ConcurrencyLimit, since each in-flight verify
+	// holds open roughly one backend request at a time.
+	concurrency := scan.ConcurrencyLimit
+	if scan.MaxInFlightRequests > 0 && scan.MaxInFlightRequests < concurrency {
+		concurrency = scan.MaxInFlightRequests
+	}
+
+	group := newBoundedGroup(ctx, concurrency)
+	stats := &verifyStats{}
+	cmd.warn = newRateLimitedLogger(cmd.WarnInterval)
+
+	for _, id := range blockIDs {
+		id := id
+
+		group.Go(func(ctx context.Context) error {
+			severity := cmd.verifyBlock(ctx, r, id, scan.BlockMetaTimeout)
+			stats.record(severity)
+
+			if severity == "critical" && cmd.HaltOnError {
+				haltOnError(ctx, id)
+			}
+
+			return ctx.Err()
+		})
+	}
+
+	// a verification failure is recorded in stats, not returned, so every
+	// block is still scanned; Wait only ever surfaces a cancellation.
+	if err := group.Wait(); err != nil {
+		return err
+	}
+
+	cmd.warn.flush()
+
+	fmt.Printf("verify complete: scanned=%d ok=%d warnings=%d critical=%d\n",
+		stats.scanned, stats.ok, stats.warnings, stats.critical)
+
+	return nil
+}
+
+// verifyBlock downloads the block's meta, bloom filter and index and checks
+// them against a handful of invariants. It returns one of "ok", "warning" or
+// "critical" describing the worst problem found. timeout, when positive, is
+// applied fresh around each individual backend call rather than once for
+// the whole block, so a slow meta fetch doesn't eat into the bloom/index
+// reads' budget.
+func (cmd *verifyCmd) verifyBlock(ctx context.Context, r tempodb_backend.Reader, id uuid.UUID, timeout time.Duration) string {
+	meta, compactedMeta, err := blockMeta(ctx, r, id, cmd.TenantID, timeout)
+	if err != nil {
+		cmd.warn.warn("meta not found", "warning: tenant=%s block=%s could not load meta: %v", cmd.TenantID, id, err)
+		return "warning"
+	}
+
+	unified := getMeta(meta, compactedMeta, time.Hour)
+
+	if unified.id != id {
+		fmt.Printf("critical: tenant=%s block=%s meta.BlockID %s does not match path\n", cmd.TenantID, id, unified.id)
+		return "critical"
+	}
+
+	if unified.objects <= 0 {
+		fmt.Printf("critical: tenant=%s block=%s has TotalObjects=%d\n", cmd.TenantID, id, unified.objects)
+		return "critical"
+	}
+
+	if !unified.start.Before(unified.end) {
+		fmt.Printf("critical: tenant=%s block=%s start time %s is not before end time %s\n", cmd.TenantID, id, unified.start, unified.end)
+		return "critical"
+	}
+
+	if unified.compacted {
+		// bloom/index for compacted blocks may already be removed by the
+		// compactor, so we can't verify them further.
+		return "ok"
+	}
+
+	bloomCtx, cancel := withTimeout(ctx, timeout)
+	buff, err := r.Read(bloomCtx, fileNameBloom, id, cmd.TenantID)
+	cancel()
+	if err != nil {
+		cmd.warn.warn("bloom read failed", "warning: tenant=%s block=%s failed to read bloom filter: %v", cmd.TenantID, id, err)
+		return "warning"
+	}
+	if _, err := encoding.UnmarshalBloomFilter(buff); err != nil {
+		fmt.Printf("critical: tenant=%s block=%s bloom filter failed to parse: %v\n", cmd.TenantID, id, err)
+		return "critical"
+	}
+
+	indexCtx, cancel := withTimeout(ctx, timeout)
+	buff, err = r.Read(indexCtx, fileNameIndex, id, cmd.TenantID)
+	cancel()
+	if err != nil {
+		cmd.warn.warn("index read failed", "warning: tenant=%s block=%s failed to read index: %v", cmd.TenantID, id, err)
+		return "warning"
+	}
+	records, err := encoding.UnmarshalRecords(buff)
+	if err != nil {
+		fmt.Printf("critical: tenant=%s block=%s index failed to parse: %v\n", cmd.TenantID, id, err)
+		return "critical"
+	}
+	if len(records) != unified.objects {
+		fmt.Printf("critical: tenant=%s block=%s index has %d records, meta.TotalObjects=%d\n", cmd.TenantID, id, len(records), unified.objects)
+		return "critical"
+	}
+
+	return "ok"
+}
+
+// blockMeta fetches a block's meta, trying the active path first and falling
+// back to the compacted path, mirroring the two ways getMeta can be fed.
+// Each backend call gets its own fresh timeout.
+func blockMeta(ctx context.Context, r tempodb_backend.Reader, id uuid.UUID, tenantID string, timeout time.Duration) (*encoding.BlockMeta, *encoding.CompactedBlockMeta, error) {
+	metaCtx, cancel := withTimeout(ctx, timeout)
+	meta, err := r.BlockMeta(metaCtx, id, tenantID)
+	cancel()
+	if err == nil {
+		return meta, nil, nil
+	}
+
+	compactedCtx, cancel := withTimeout(ctx, timeout)
+	compactedMeta, cErr := r.CompactedBlockMeta(compactedCtx, id, tenantID)
+	cancel()
+	if cErr == nil {
+		return nil, compactedMeta, nil
+	}
+
+	return nil, nil, err
+}
+
+// withTimeout derives ctx with its own fresh deadline of timeout, so a
+// sequence of backend calls each get the full budget instead of sharing one
+// deadline across the whole sequence. A non-positive timeout disables this
+// and returns ctx unchanged.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// haltOnError blocks until ctx is cancelled (Ctrl-C/SIGTERM) so an operator
+// can attach a debugger/profiler to the running process and inspect the
+// offending block, mirroring Thanos compactor's --halt-on-error behavior.
+func haltOnError(ctx context.Context, id uuid.UUID) {
+	fmt.Printf("CRITICAL: block %s failed verification, halting due to --halt-on-error\n", id)
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fmt.Printf("still halted on block %s, attach to pid %d or Ctrl-C to exit\n", id, os.Getpid())
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -11,6 +12,7 @@ import (
 	"github.com/grafana/tempo/cmd/tempo/app"
 	tempodb_backend "github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/tracing"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"gopkg.in/yaml.v2"
 
@@ -21,6 +23,9 @@ import (
 
 type globalOptions struct {
 	ConfigFile string `type:"path" short:"c" help:"Path to tempo config file"`
+
+	TraceEndpoint string `name:"trace-endpoint" help:"enable tracing of backend calls and send spans to this endpoint, optional"`
+	TraceExporter string `name:"trace-exporter" help:"tracing exporter to use" enum:"jaeger" default:"jaeger"`
 }
 
 type backendOptions struct {
@@ -30,6 +35,18 @@ type backendOptions struct {
 	S3Endpoint string `name:"s3-endpoint" help:"s3 endpoint (s3.dualstack.us-east-2.amazonaws.com), optional, overrides endpoint in config file"`
 	S3User     string `name:"s3-user" help:"s3 username, optional, overrides username in config file"`
 	S3Pass     string `name:"s3-pass" help:"s3 password, optional, overrides password in config file"`
+
+	Concurrency      int           `help:"number of backend requests to run in parallel, optional, overrides scan.concurrency_limit in config file"`
+	BlockMetaTimeout time.Duration `help:"timeout applied to each backend meta/block request, optional, overrides scan.block_meta_timeout in config file"`
+	MaxInFlight      int           `name:"max-inflight" help:"maximum number of in-flight backend requests, optional, overrides scan.max_inflight_requests in config file"`
+}
+
+// scanConfig bundles the concurrency/timeout knobs that control how hard a
+// CLI command is allowed to hit the backend while scanning a bucket.
+type scanConfig struct {
+	ConcurrencyLimit    int
+	BlockMetaTimeout    time.Duration
+	MaxInFlightRequests int
 }
 
 var cli struct {
@@ -41,6 +58,8 @@ var cli struct {
 	} `cmd:""`
 
 	Query queryCmd `cmd:"" help:"query tempo api"`
+
+	Verify verifyCmd `cmd:"" help:"verify blocks in a bucket, halting on corruption"`
 }
 
 func main() {
@@ -50,11 +69,19 @@ func main() {
 			//Compact: true,
 		}),
 	)
-	err := ctx.Run(&cli.globalOptions)
+
+	closer, err := initTracing(cli.globalOptions.TraceEndpoint, cli.globalOptions.TraceExporter)
+	ctx.FatalIfErrorf(err)
+
+	// ctx.FatalIfErrorf below calls os.Exit on a command error, which would
+	// skip a deferred closer.Close and drop every span from this run, so
+	// flush explicitly before it instead of relying on defer.
+	err = ctx.Run(&cli.globalOptions)
+	closer.Close()
 	ctx.FatalIfErrorf(err)
 }
 
-func loadBackend(b *backendOptions, g *globalOptions) (tempodb_backend.Reader, tempodb_backend.Compactor, error) {
+func loadBackend(b *backendOptions, g *globalOptions) (tempodb_backend.Reader, tempodb_backend.Compactor, scanConfig, error) {
 	// Defaults
 	cfg := app.Config{}
 	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
@@ -63,12 +90,12 @@ func loadBackend(b *backendOptions, g *globalOptions) (tempodb_backend.Reader, t
 	if g.ConfigFile != "" {
 		buff, err := ioutil.ReadFile(g.ConfigFile)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to read configFile %s: %w", g.ConfigFile, err)
+			return nil, nil, scanConfig{}, fmt.Errorf("failed to read configFile %s: %w", g.ConfigFile, err)
 		}
 
 		err = yaml.UnmarshalStrict(buff, &cfg)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to parse configFile %s: %w", g.ConfigFile, err)
+			return nil, nil, scanConfig{}, fmt.Errorf("failed to parse configFile %s: %w", g.ConfigFile, err)
 		}
 	}
 
@@ -87,6 +114,18 @@ func loadBackend(b *backendOptions, g *globalOptions) (tempodb_backend.Reader, t
 		cfg.StorageConfig.Trace.S3.Endpoint = b.S3Endpoint
 	}
 
+	if b.Concurrency != 0 {
+		cfg.Scan.ConcurrencyLimit = b.Concurrency
+	}
+
+	if b.BlockMetaTimeout != 0 {
+		cfg.Scan.BlockMetaTimeout = b.BlockMetaTimeout
+	}
+
+	if b.MaxInFlight != 0 {
+		cfg.Scan.MaxInFlightRequests = b.MaxInFlight
+	}
+
 	var err error
 	var r tempodb_backend.Reader
 	var c tempodb_backend.Compactor
@@ -103,10 +142,20 @@ func loadBackend(b *backendOptions, g *globalOptions) (tempodb_backend.Reader, t
 	}
 
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, scanConfig{}, err
 	}
 
-	return r, c, nil
+	scan := scanConfig{
+		ConcurrencyLimit:    cfg.Scan.ConcurrencyLimit,
+		BlockMetaTimeout:    cfg.Scan.BlockMetaTimeout,
+		MaxInFlightRequests: cfg.Scan.MaxInFlightRequests,
+	}
+
+	if g.TraceEndpoint != "" {
+		r = tracing.NewTracingReader(r)
+	}
+
+	return r, c, scan, nil
 }
 
 type unifiedBlockMeta struct {
@@ -153,30 +202,75 @@ func getMeta(meta *encoding.BlockMeta, compactedMeta *encoding.CompactedBlockMet
 	}
 }
 
-// boundedWaitGroup like a normal wait group except limits number of active goroutines to given capacity.
-type boundedWaitGroup struct {
-	wg sync.WaitGroup
-	ch chan struct{} // Chan buffer size is used to limit concurrency.
-}
+// boundedGroup is like a normal wait group except it limits the number of
+// active goroutines to a given capacity, cancels its context on the first
+// error returned by a worker so the rest can stop making backend calls, and
+// surfaces that error from Wait.
+type boundedGroup struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	sem    chan struct{}
+	wg     sync.WaitGroup
 
-func newBoundedWaitGroup(cap int) boundedWaitGroup {
-	return boundedWaitGroup{ch: make(chan struct{}, cap)}
+	mtx sync.Mutex
+	err error
 }
 
-func (bwg *boundedWaitGroup) Add(delta int) {
-	for i := 0; i > delta; i-- {
-		<-bwg.ch
+// defaultGroupConcurrency is used whenever a caller passes a non-positive
+// cap, since an unbuffered semaphore (cap 0) would block the first Go call
+// forever.
+const defaultGroupConcurrency = 10
+
+func newBoundedGroup(ctx context.Context, cap int) *boundedGroup {
+	if cap <= 0 {
+		cap = defaultGroupConcurrency
 	}
-	for i := 0; i < delta; i++ {
-		bwg.ch <- struct{}{}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &boundedGroup{
+		ctx:    ctx,
+		cancel: cancel,
+		sem:    make(chan struct{}, cap),
 	}
-	bwg.wg.Add(delta)
 }
 
-func (bwg *boundedWaitGroup) Done() {
-	bwg.Add(-1)
+// Go runs f in a new goroutine once a slot is free, or returns without
+// starting it if the group's context is cancelled first. If f returns a
+// non-nil error, the group's context is cancelled so other in-flight and
+// future workers can unwind early.
+func (g *boundedGroup) Go(f func(ctx context.Context) error) {
+	if g.ctx.Err() != nil {
+		return
+	}
+
+	select {
+	case g.sem <- struct{}{}:
+	case <-g.ctx.Done():
+		return
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() { <-g.sem }()
+
+		if err := f(g.ctx); err != nil {
+			g.mtx.Lock()
+			if g.err == nil {
+				g.err = err
+				g.cancel()
+			}
+			g.mtx.Unlock()
+		}
+	}()
 }
 
-func (bwg *boundedWaitGroup) Wait() {
-	bwg.wg.Wait()
+// Wait blocks until every started goroutine has returned and reports the
+// first error any of them returned, if any.
+func (g *boundedGroup) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+
+	return g.err
 }
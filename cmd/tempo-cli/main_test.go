@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewBoundedGroupZeroCapDoesNotHang(t *testing.T) {
+	g := newBoundedGroup(context.Background(), 0)
+
+	done := make(chan struct{})
+	g.Go(func(ctx context.Context) error {
+		close(done)
+		return nil
+	})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Go never ran a task with a non-positive cap")
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBoundedGroupLimitsConcurrency(t *testing.T) {
+	const capacity = 3
+	g := newBoundedGroup(context.Background(), capacity)
+
+	var current, max int32
+	for i := 0; i < 20; i++ {
+		g.Go(func(ctx context.Context) error {
+			n := atomic.AddInt32(&current, 1)
+			for {
+				old := atomic.LoadInt32(&max)
+				if n <= old || atomic.CompareAndSwapInt32(&max, old, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&current, -1)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if max > capacity {
+		t.Fatalf("expected at most %d concurrent goroutines, saw %d", capacity, max)
+	}
+}
+
+func TestBoundedGroupCancelsOnFirstError(t *testing.T) {
+	g := newBoundedGroup(context.Background(), 2)
+	wantErr := errors.New("boom")
+
+	// the failing worker waits for the second one to start, since Go does not
+	// start new work once the group's context is already cancelled.
+	started := make(chan struct{})
+	g.Go(func(ctx context.Context) error {
+		<-started
+		return wantErr
+	})
+
+	g.Go(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	<-started
+
+	if err := g.Wait(); !errors.Is(err, wantErr) {
+		t.Fatalf("expected Wait to return %v, got %v", wantErr, err)
+	}
+}
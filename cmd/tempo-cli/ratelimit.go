@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimitedLogger prints at most one warning per key every interval,
+// counting how many were suppressed in between so a summary can be printed
+// once a scan finishes. Used for transient, high-volume per-block problems
+// (missing meta, read errors) so a bucket scan with tens of thousands of
+// blocks doesn't drown its output; callers should print genuine corruption
+// findings directly instead of through this logger.
+type rateLimitedLogger struct {
+	interval time.Duration
+
+	mtx        sync.Mutex
+	limiters   map[string]*rate.Limiter
+	suppressed map[string]int
+}
+
+func newRateLimitedLogger(interval time.Duration) *rateLimitedLogger {
+	return &rateLimitedLogger{
+		interval:   interval,
+		limiters:   make(map[string]*rate.Limiter),
+		suppressed: make(map[string]int),
+	}
+}
+
+// warn prints format/args under key, at most once per interval. Calls that
+// arrive while key is still rate limited are counted instead of printed.
+func (l *rateLimitedLogger) warn(key, format string, args ...interface{}) {
+	l.mtx.Lock()
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Every(l.interval), 1)
+		l.limiters[key] = limiter
+	}
+
+	if !limiter.Allow() {
+		l.suppressed[key]++
+		l.mtx.Unlock()
+		return
+	}
+	l.mtx.Unlock()
+
+	fmt.Printf(format+"\n", args...)
+}
+
+// flush prints a summary of how many warnings were suppressed per key and
+// resets the counters. Call once after a scan completes.
+func (l *rateLimitedLogger) flush() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	for key, count := range l.suppressed {
+		if count > 0 {
+			fmt.Printf("suppressed %d %q warnings (at most one per %s printed)\n", count, key, l.interval)
+		}
+		delete(l.suppressed, key)
+	}
+}
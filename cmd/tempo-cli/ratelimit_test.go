@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimitedLoggerSuppressesWithinInterval(t *testing.T) {
+	l := newRateLimitedLogger(time.Hour)
+
+	for i := 0; i < 5; i++ {
+		l.warn("key", "msg %d", i)
+	}
+
+	l.mtx.Lock()
+	suppressed := l.suppressed["key"]
+	l.mtx.Unlock()
+
+	if suppressed != 4 {
+		t.Fatalf("expected 4 suppressed warnings, got %d", suppressed)
+	}
+}
+
+func TestRateLimitedLoggerKeysAreIndependent(t *testing.T) {
+	l := newRateLimitedLogger(time.Hour)
+
+	l.warn("a", "msg")
+	l.warn("a", "msg")
+	l.warn("b", "msg")
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.suppressed["a"] != 1 {
+		t.Fatalf("expected 1 suppressed warning for key a, got %d", l.suppressed["a"])
+	}
+	if l.suppressed["b"] != 0 {
+		t.Fatalf("expected 0 suppressed warnings for key b, got %d", l.suppressed["b"])
+	}
+}
+
+func TestRateLimitedLoggerAllowsAfterInterval(t *testing.T) {
+	l := newRateLimitedLogger(10 * time.Millisecond)
+
+	l.warn("key", "msg")
+	time.Sleep(20 * time.Millisecond)
+	l.warn("key", "msg")
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.suppressed["key"] != 0 {
+		t.Fatalf("expected no suppression once the interval elapsed, got %d", l.suppressed["key"])
+	}
+}
+
+func TestRateLimitedLoggerFlushResetsCounts(t *testing.T) {
+	l := newRateLimitedLogger(time.Hour)
+
+	l.warn("key", "msg")
+	l.warn("key", "msg")
+
+	l.flush()
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.suppressed["key"] != 0 {
+		t.Fatalf("expected suppressed count to reset after flush, got %d", l.suppressed["key"])
+	}
+}
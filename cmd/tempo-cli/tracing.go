@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	opentracing "github.com/opentracing/opentracing-go"
+	jaeger "github.com/uber/jaeger-client-go"
+	jaegercfg "github.com/uber/jaeger-client-go/config"
+)
+
+type noopCloser struct{}
+
+func (noopCloser) Close() error { return nil }
+
+// initTracing sets up the global tracer used by tempodb/backend/tracing's
+// reader wrapper, returning a closer to flush and shut it down at exit. If
+// endpoint is empty, tracing is left disabled and the returned closer is a
+// no-op.
+func initTracing(endpoint, exporter string) (io.Closer, error) {
+	if endpoint == "" {
+		return noopCloser{}, nil
+	}
+
+	switch exporter {
+	case "jaeger":
+		return initJaegerTracing(endpoint)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q, must be jaeger", exporter)
+	}
+}
+
+func initJaegerTracing(endpoint string) (io.Closer, error) {
+	cfg := jaegercfg.Configuration{
+		ServiceName: "tempo-cli",
+		Sampler: &jaegercfg.SamplerConfig{
+			Type:  jaeger.SamplerTypeConst,
+			Param: 1,
+		},
+		Reporter: &jaegercfg.ReporterConfig{
+			LocalAgentHostPort: endpoint,
+			LogSpans:           false,
+		},
+	}
+
+	tracer, closer, err := cfg.NewTracer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init jaeger tracer: %w", err)
+	}
+
+	opentracing.SetGlobalTracer(tracer)
+
+	return closer, nil
+}
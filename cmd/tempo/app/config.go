@@ -0,0 +1,50 @@
+package app
+
+import (
+	"flag"
+	"time"
+
+	"github.com/grafana/tempo/tempodb/backend/gcs"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/s3"
+)
+
+// Config is the root configuration for the tempo server, also parsed by
+// tempo-cli so it can talk to the same storage backend with the same
+// defaults.
+type Config struct {
+	StorageConfig StorageConfig `yaml:"storage"`
+	Scan          Scan          `yaml:"scan"`
+}
+
+// StorageConfig configures the storage backends tempo reads and writes
+// traces to.
+type StorageConfig struct {
+	Trace TraceStorageConfig `yaml:"trace"`
+}
+
+// TraceStorageConfig selects and configures a single trace storage backend.
+type TraceStorageConfig struct {
+	Backend string       `yaml:"backend"`
+	Local   local.Config `yaml:"local"`
+	GCS     gcs.Config   `yaml:"gcs"`
+	S3      s3.Config    `yaml:"s3"`
+}
+
+// Scan controls how hard tools like tempo-cli are allowed to hit the backend
+// while scanning a bucket.
+type Scan struct {
+	ConcurrencyLimit    int           `yaml:"concurrency_limit"`
+	BlockMetaTimeout    time.Duration `yaml:"block_meta_timeout"`
+	MaxInFlightRequests int           `yaml:"max_inflight_requests"`
+}
+
+// RegisterFlagsAndApplyDefaults fills in Config's defaults. It registers no
+// flags on f, and prefix is unused.
+func (c *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	c.Scan = Scan{
+		ConcurrencyLimit:    10,
+		BlockMetaTimeout:    30 * time.Second,
+		MaxInFlightRequests: 0, // 0 == unlimited
+	}
+}
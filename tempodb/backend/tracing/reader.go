@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	opentracing "github.com/opentracing/opentracing-go"
+	otlog "github.com/opentracing/opentracing-go/log"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+)
+
+// reader wraps a backend.Reader and starts a span around every call,
+// tagging the tenant, block and byte size involved and recording any error
+// returned, so a slow or failing object-storage call shows up in a trace
+// instead of only as elapsed wall-clock time.
+type reader struct {
+	r backend.Reader
+}
+
+// NewTracingReader wraps r so every call is recorded as a span. It can be
+// shared by tempo-cli, the ingester and the querier, wherever a
+// backend.Reader is constructed.
+func NewTracingReader(r backend.Reader) backend.Reader {
+	return &reader{r: r}
+}
+
+func (t *reader) Blocks(ctx context.Context, tenantID string) ([]uuid.UUID, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "backend.Blocks")
+	defer span.Finish()
+	span.SetTag("tenant", tenantID)
+
+	ids, err := t.r.Blocks(ctx, tenantID)
+	span.SetTag("blocks", len(ids))
+	logError(span, err)
+
+	return ids, err
+}
+
+func (t *reader) BlockMeta(ctx context.Context, blockID uuid.UUID, tenantID string) (*encoding.BlockMeta, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "backend.BlockMeta")
+	defer span.Finish()
+	span.SetTag("tenant", tenantID)
+	span.SetTag("block", blockID.String())
+
+	meta, err := t.r.BlockMeta(ctx, blockID, tenantID)
+	logError(span, err)
+
+	return meta, err
+}
+
+func (t *reader) CompactedBlockMeta(ctx context.Context, blockID uuid.UUID, tenantID string) (*encoding.CompactedBlockMeta, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "backend.CompactedBlockMeta")
+	defer span.Finish()
+	span.SetTag("tenant", tenantID)
+	span.SetTag("block", blockID.String())
+
+	meta, err := t.r.CompactedBlockMeta(ctx, blockID, tenantID)
+	logError(span, err)
+
+	return meta, err
+}
+
+func (t *reader) Read(ctx context.Context, name string, blockID uuid.UUID, tenantID string) ([]byte, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "backend.Read")
+	defer span.Finish()
+	span.SetTag("tenant", tenantID)
+	span.SetTag("block", blockID.String())
+	span.SetTag("object", name)
+
+	buff, err := t.r.Read(ctx, name, blockID, tenantID)
+	span.SetTag("bytes", len(buff))
+	logError(span, err)
+
+	return buff, err
+}
+
+func (t *reader) ReadRange(ctx context.Context, name string, blockID uuid.UUID, tenantID string, offset uint64, buffer []byte) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "backend.ReadRange")
+	defer span.Finish()
+	span.SetTag("tenant", tenantID)
+	span.SetTag("block", blockID.String())
+	span.SetTag("object", name)
+	span.SetTag("offset", offset)
+	span.SetTag("bytes", len(buffer))
+
+	err := t.r.ReadRange(ctx, name, blockID, tenantID, offset, buffer)
+	logError(span, err)
+
+	return err
+}
+
+func (t *reader) Shutdown() {
+	span := opentracing.StartSpan("backend.Shutdown")
+	defer span.Finish()
+
+	t.r.Shutdown()
+}
+
+func logError(span opentracing.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.SetTag("error", true)
+	span.LogFields(otlog.Error(err))
+}